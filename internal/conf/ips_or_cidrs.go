@@ -0,0 +1,11 @@
+package conf
+
+// IPsOrCIDRs is a list of IPs and/or CIDR blocks, e.g. the set of trusted
+// reverse-proxy addresses that gin should read X-Forwarded-For from.
+type IPsOrCIDRs []string
+
+// ToTrustedProxies converts the list into the format expected by
+// gin.Engine.SetTrustedProxies.
+func (i IPsOrCIDRs) ToTrustedProxies() []string {
+	return []string(i)
+}
@@ -0,0 +1,101 @@
+package conf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ErrFingerprintMismatch is returned by LockedConfig.Write and
+// LockedConfig.Patch when the fingerprint supplied by the caller doesn't
+// match the fingerprint of the config at the time of the call, i.e. someone
+// else modified it in between the caller's read and write.
+var ErrFingerprintMismatch = fmt.Errorf("config fingerprint mismatch")
+
+// LockedConfig serializes reads and writes to a Conf and computes a
+// fingerprint (a SHA-256 hash of its canonical JSON encoding) that callers
+// can use for optimistic concurrency control, e.g. an HTTP API that accepts
+// an If-Match header.
+type LockedConfig struct {
+	mutex sync.Mutex
+	conf  *Conf
+}
+
+// NewLockedConfig allocates a LockedConfig wrapping conf.
+func NewLockedConfig(conf *Conf) *LockedConfig {
+	return &LockedConfig{conf: conf}
+}
+
+func fingerprint(conf *Conf) (string, error) {
+	byts, err := json.Marshal(conf)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(byts)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Read returns a copy of the current config together with its fingerprint.
+func (l *LockedConfig) Read() (*Conf, string, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	fp, err := fingerprint(l.conf)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return l.conf.Clone(), fp, nil
+}
+
+// Write replaces the config with newConf, but only if expectedFingerprint
+// still matches the fingerprint of the config currently held. It returns
+// ErrFingerprintMismatch otherwise, so that the caller can reject the
+// request (e.g. with an HTTP 412 Precondition Failed) instead of silently
+// overwriting a concurrent edit.
+func (l *LockedConfig) Write(expectedFingerprint string, newConf *Conf) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	curFingerprint, err := fingerprint(l.conf)
+	if err != nil {
+		return err
+	}
+
+	if curFingerprint != expectedFingerprint {
+		return ErrFingerprintMismatch
+	}
+
+	l.conf = newConf
+	return nil
+}
+
+// Patch reads the current config, invokes cb with it and applies cb's
+// mutations, but only if expectedFingerprint still matches. cb must mutate
+// the Conf it receives in place. This is the primitive used by the
+// JSON-Pointer-based subtree PATCH endpoints, so that a single field can be
+// updated without racing against a concurrent edit or the reload watcher.
+func (l *LockedConfig) Patch(expectedFingerprint string, cb func(*Conf) error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	curFingerprint, err := fingerprint(l.conf)
+	if err != nil {
+		return err
+	}
+
+	if curFingerprint != expectedFingerprint {
+		return ErrFingerprintMismatch
+	}
+
+	newConf := l.conf.Clone()
+	if err := cb(newConf); err != nil {
+		return err
+	}
+
+	l.conf = newConf
+	return nil
+}
@@ -0,0 +1,32 @@
+package conf
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StringDuration is a time.Duration that (un)marshals as a Go duration
+// string (e.g. "10s") rather than a number of nanoseconds, so it reads
+// naturally in YAML/JSON config and in API request/response bodies.
+type StringDuration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d StringDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *StringDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = StringDuration(dur)
+	return nil
+}
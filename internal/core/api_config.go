@@ -0,0 +1,272 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/bluenviron/mediamtx/internal/conf"
+	"github.com/bluenviron/mediamtx/internal/deadline"
+)
+
+// api exposes the config and per-session REST API. Fingerprint-guarded
+// config edits mean every PATCH/POST/DELETE must carry an If-Match header
+// matching the fingerprint returned by the last GET, so that a UI and the
+// reload watcher can't silently clobber each other's changes.
+type api struct {
+	conf *conf.LockedConfig
+
+	rtspDeadlines *deadline.Registry[uuid.UUID]
+	rtmpDeadlines *deadline.Registry[uuid.UUID]
+	srtDeadlines  *deadline.Registry[uuid.UUID]
+}
+
+func newAPI(lc *conf.LockedConfig) *api {
+	return &api{
+		conf:          lc,
+		rtspDeadlines: deadline.NewRegistry[uuid.UUID](),
+		rtmpDeadlines: deadline.NewRegistry[uuid.UUID](),
+		srtDeadlines:  deadline.NewRegistry[uuid.UUID](),
+	}
+}
+
+// mountConfigRoutes registers the config endpoints on router.
+func (a *api) mountConfigRoutes(router *gin.Engine) {
+	group := router.Group("/v3/config/paths")
+	group.GET("/get/:name", a.onConfigPathGet)
+	group.GET("/get/:name/*jsonpath", a.onConfigPathGetSubtree)
+	group.POST("/add/:name", a.onConfigPathSet)
+	group.PATCH("/edit/:name", a.onConfigPathSet)
+	group.PATCH("/patch/:name/*jsonpath", a.onConfigPathPatchSubtree)
+	group.DELETE("/delete/:name", a.onConfigPathDelete)
+}
+
+func (a *api) requireIfMatch(ctx *gin.Context) (string, bool) {
+	fp := ctx.GetHeader("If-Match")
+	if fp == "" {
+		ctx.JSON(http.StatusPreconditionRequired, apiError{Error: "If-Match header is required"})
+		return "", false
+	}
+	return fp, true
+}
+
+// respondFingerprintMismatch writes a 412 Precondition Failed together
+// with the fingerprint the caller should retry with.
+func (a *api) respondFingerprintMismatch(ctx *gin.Context) {
+	_, curFp, err := a.conf.Read()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusPreconditionFailed, apiConfigPreconditionFailed{
+		Error:              "config was modified concurrently, fetch it again and retry",
+		CurrentFingerprint: curFp,
+	})
+}
+
+func (a *api) onConfigPathGet(ctx *gin.Context) {
+	c, fp, err := a.conf.Read()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	p, ok := c.Paths[ctx.Param("name")]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, apiError{Error: errPathNotConfigured.Error()})
+		return
+	}
+
+	ctx.Header("ETag", fp)
+	ctx.JSON(http.StatusOK, p)
+}
+
+func (a *api) onConfigPathGetSubtree(ctx *gin.Context) {
+	c, fp, err := a.conf.Read()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	p, ok := c.Paths[ctx.Param("name")]
+	if !ok {
+		ctx.JSON(http.StatusNotFound, apiError{Error: errPathNotConfigured.Error()})
+		return
+	}
+
+	generic, err := toGeneric(p)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	val, err := jsonPointerGet(generic, ctx.Param("jsonpath"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, apiError{Error: err.Error()})
+		return
+	}
+
+	byts, err := json.Marshal(val)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiError{Error: err.Error()})
+		return
+	}
+
+	ctx.Header("ETag", fp)
+	ctx.JSON(http.StatusOK, apiConfigSubtree{Value: byts})
+}
+
+// onConfigPathSet creates or fully replaces a path config. It backs both
+// POST /add/{name} and PATCH /edit/{name}.
+func (a *api) onConfigPathSet(ctx *gin.Context) {
+	fp, ok := a.requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	var body conf.OptionalPath
+	if err := ctx.BindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+	body.Name = ctx.Param("name")
+
+	err := a.conf.Patch(fp, func(c *conf.Conf) error {
+		c.Paths[body.Name] = &body
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, conf.ErrFingerprintMismatch) {
+			a.respondFingerprintMismatch(ctx)
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	ctx.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) onConfigPathPatchSubtree(ctx *gin.Context) {
+	fp, ok := a.requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	var body apiConfigSubtree
+	if err := ctx.BindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	name := ctx.Param("name")
+	pointer := ctx.Param("jsonpath")
+
+	err := a.conf.Patch(fp, func(c *conf.Conf) error {
+		p, ok := c.Paths[name]
+		if !ok {
+			return fmt.Errorf("path '%s' is not configured: %w", name, errPathNotConfigured)
+		}
+
+		generic, err := toGeneric(p)
+		if err != nil {
+			return err
+		}
+
+		var newVal interface{}
+		if err := json.Unmarshal(body.Value, &newVal); err != nil {
+			return err
+		}
+
+		generic, err = jsonPointerSet(generic, pointer, newVal)
+		if err != nil {
+			return err
+		}
+
+		var newPath conf.OptionalPath
+		merged, err := json.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(merged, &newPath); err != nil {
+			return err
+		}
+
+		// the patch touched /name: the map key must follow it, or the path
+		// ends up stored under its old key while claiming a different one.
+		if newPath.Name != name {
+			if newPath.Name == "" {
+				return fmt.Errorf("name cannot be empty")
+			}
+			if _, exists := c.Paths[newPath.Name]; exists {
+				return fmt.Errorf("path '%s' is already configured", newPath.Name)
+			}
+			delete(c.Paths, name)
+		}
+
+		c.Paths[newPath.Name] = &newPath
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, conf.ErrFingerprintMismatch):
+			a.respondFingerprintMismatch(ctx)
+		case errors.Is(err, errPathNotConfigured):
+			ctx.JSON(http.StatusNotFound, apiError{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) onConfigPathDelete(ctx *gin.Context) {
+	fp, ok := a.requireIfMatch(ctx)
+	if !ok {
+		return
+	}
+
+	name := ctx.Param("name")
+
+	err := a.conf.Patch(fp, func(c *conf.Conf) error {
+		if _, ok := c.Paths[name]; !ok {
+			return fmt.Errorf("path '%s' is not configured: %w", name, errPathNotConfigured)
+		}
+		delete(c.Paths, name)
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, conf.ErrFingerprintMismatch):
+			a.respondFingerprintMismatch(ctx)
+		case errors.Is(err, errPathNotConfigured):
+			ctx.JSON(http.StatusNotFound, apiError{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.Writer.WriteHeader(http.StatusNoContent)
+}
+
+func toGeneric(v interface{}) (interface{}, error) {
+	byts, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(byts, &generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/mediamtx/internal/conf"
+)
+
+func newTestAPIRouter(t *testing.T) (*gin.Engine, *api) {
+	gin.SetMode(gin.TestMode)
+
+	lc := conf.NewLockedConfig(&conf.Conf{
+		Paths: map[string]*conf.OptionalPath{
+			"mystream": {Name: "mystream"},
+		},
+	})
+	a := newAPI(lc)
+
+	router := gin.New()
+	a.mountConfigRoutes(router)
+
+	return router, a
+}
+
+func TestAPIConfigFingerprintMismatchReturns412(t *testing.T) {
+	router, _ := newTestAPIRouter(t)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v3/config/paths/get/mystream", nil)
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+	require.Equal(t, http.StatusOK, getRec.Code)
+	staleFP := getRec.Header().Get("ETag")
+	require.NotEmpty(t, staleFP)
+
+	// a first edit with the correct fingerprint succeeds and, since it
+	// changes the config's content, rotates it.
+	patchReq := httptest.NewRequest(http.MethodPatch, "/v3/config/paths/edit/mystream",
+		strings.NewReader(`{"name":"mystream","user":"alice"}`))
+	patchReq.Header.Set("If-Match", staleFP)
+	patchRec := httptest.NewRecorder()
+	router.ServeHTTP(patchRec, patchReq)
+	require.Equal(t, http.StatusNoContent, patchRec.Code)
+
+	// replaying the now-stale fingerprint must be rejected with 412, not
+	// silently applied over whatever changed in between.
+	staleReq := httptest.NewRequest(http.MethodPatch, "/v3/config/paths/edit/mystream",
+		strings.NewReader(`{"name":"mystream"}`))
+	staleReq.Header.Set("If-Match", staleFP)
+	staleRec := httptest.NewRecorder()
+	router.ServeHTTP(staleRec, staleReq)
+	require.Equal(t, http.StatusPreconditionFailed, staleRec.Code)
+}
+
+func TestAPIConfigPatchRequiresIfMatch(t *testing.T) {
+	router, _ := newTestAPIRouter(t)
+
+	req := httptest.NewRequest(http.MethodPatch, "/v3/config/paths/edit/mystream",
+		strings.NewReader(`{"name":"mystream"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusPreconditionRequired, rec.Code)
+}
+
+func TestAPIConfigSubtreeGetAndPatch(t *testing.T) {
+	router, _ := newTestAPIRouter(t)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v3/config/paths/get/mystream/name", nil)
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+	require.Equal(t, http.StatusOK, getRec.Code)
+	fp := getRec.Header().Get("ETag")
+	require.Contains(t, getRec.Body.String(), "mystream")
+
+	patchReq := httptest.NewRequest(http.MethodPatch, "/v3/config/paths/patch/mystream/name",
+		strings.NewReader(`{"value":"renamed"}`))
+	patchReq.Header.Set("If-Match", fp)
+	patchRec := httptest.NewRecorder()
+	router.ServeHTTP(patchRec, patchReq)
+	require.Equal(t, http.StatusNoContent, patchRec.Code)
+
+	// the map key must have followed the rename: the old name is gone and
+	// the new one resolves to the (renamed) path config.
+	oldReq := httptest.NewRequest(http.MethodGet, "/v3/config/paths/get/mystream", nil)
+	oldRec := httptest.NewRecorder()
+	router.ServeHTTP(oldRec, oldReq)
+	require.Equal(t, http.StatusNotFound, oldRec.Code)
+
+	newReq := httptest.NewRequest(http.MethodGet, "/v3/config/paths/get/renamed", nil)
+	newRec := httptest.NewRecorder()
+	router.ServeHTTP(newRec, newReq)
+	require.Equal(t, http.StatusOK, newRec.Code)
+	require.Contains(t, newRec.Body.String(), "renamed")
+}
+
+func TestAPIConfigNotConfiguredIs404EverywhereBehindIfMatch(t *testing.T) {
+	router, _ := newTestAPIRouter(t)
+
+	getReq := httptest.NewRequest(http.MethodGet, "/v3/config/paths/get/mystream", nil)
+	getRec := httptest.NewRecorder()
+	router.ServeHTTP(getRec, getReq)
+	fp := getRec.Header().Get("ETag")
+
+	patchReq := httptest.NewRequest(http.MethodPatch, "/v3/config/paths/patch/missing/name",
+		strings.NewReader(`{"value":"x"}`))
+	patchReq.Header.Set("If-Match", fp)
+	patchRec := httptest.NewRecorder()
+	router.ServeHTTP(patchRec, patchReq)
+	require.Equal(t, http.StatusNotFound, patchRec.Code)
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/v3/config/paths/delete/missing", nil)
+	deleteReq.Header.Set("If-Match", fp)
+	deleteRec := httptest.NewRecorder()
+	router.ServeHTTP(deleteRec, deleteReq)
+	require.Equal(t, http.StatusNotFound, deleteRec.Code)
+}
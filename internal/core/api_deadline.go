@@ -0,0 +1,58 @@
+package core
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/bluenviron/mediamtx/internal/deadline"
+)
+
+// mountDeadlineRoutes registers PATCH /v3/rtspsessions/{id}/deadline,
+// /v3/rtmpconns/{id}/deadline, /v3/srtconns/{id}/deadline and
+// /v3/webrtcsessions/{id}/deadline, each backed by its protocol's own
+// deadline.Registry. A handler looks the session up in that Registry and
+// arms the read and/or write Timer of its Pair. Arming a Timer only closes
+// its channel: nothing in this package adds entries to the RTSP, RTMP or
+// SRT registries, and while WebRTC sessions register their Pair, no code
+// here selects on Pair.Read.C() or Pair.Write.C(), so a deadline does not
+// interrupt a session's I/O.
+func (a *api) mountDeadlineRoutes(router *gin.Engine, webRTCDeadlines *deadline.Registry[uuid.UUID]) {
+	router.PATCH("/v3/rtspsessions/:id/deadline", a.onDeadline(a.rtspDeadlines))
+	router.PATCH("/v3/rtmpconns/:id/deadline", a.onDeadline(a.rtmpDeadlines))
+	router.PATCH("/v3/srtconns/:id/deadline", a.onDeadline(a.srtDeadlines))
+	router.PATCH("/v3/webrtcsessions/:id/deadline", a.onDeadline(webRTCDeadlines))
+}
+
+func (a *api) onDeadline(reg *deadline.Registry[uuid.UUID]) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiError{Error: "invalid id"})
+			return
+		}
+
+		pair := reg.Get(id)
+		if pair == nil {
+			ctx.JSON(http.StatusNotFound, apiError{Error: "session not found"})
+			return
+		}
+
+		var body apiSessionDeadlineReq
+		if err := ctx.BindJSON(&body); err != nil {
+			ctx.JSON(http.StatusBadRequest, apiError{Error: err.Error()})
+			return
+		}
+
+		if body.ReadDeadline != nil {
+			pair.Read.Set(time.Duration(*body.ReadDeadline))
+		}
+		if body.WriteDeadline != nil {
+			pair.Write.Set(time.Duration(*body.WriteDeadline))
+		}
+
+		ctx.Writer.WriteHeader(http.StatusNoContent)
+	}
+}
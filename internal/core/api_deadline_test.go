@@ -0,0 +1,58 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/mediamtx/internal/conf"
+	"github.com/bluenviron/mediamtx/internal/deadline"
+)
+
+func TestAPISessionDeadlinePatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lc := conf.NewLockedConfig(&conf.Conf{Paths: map[string]*conf.OptionalPath{}})
+	a := newAPI(lc)
+
+	id := uuid.New()
+	pair := deadline.NewPair()
+	a.rtspDeadlines.Add(id, pair)
+
+	router := gin.New()
+	a.mountDeadlineRoutes(router, deadline.NewRegistry[uuid.UUID]())
+
+	req := httptest.NewRequest(http.MethodPatch, "/v3/rtspsessions/"+id.String()+"/deadline",
+		strings.NewReader(`{"readDeadline":"50ms"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	select {
+	case <-pair.Read.C():
+	case <-time.After(time.Second):
+		t.Fatal("read deadline was not armed by the API call")
+	}
+}
+
+func TestAPISessionDeadlineUnknownSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lc := conf.NewLockedConfig(&conf.Conf{Paths: map[string]*conf.OptionalPath{}})
+	a := newAPI(lc)
+
+	router := gin.New()
+	a.mountDeadlineRoutes(router, deadline.NewRegistry[uuid.UUID]())
+
+	req := httptest.NewRequest(http.MethodPatch, "/v3/rtspsessions/"+uuid.New().String()+"/deadline",
+		strings.NewReader(`{"readDeadline":"50ms"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
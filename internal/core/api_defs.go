@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,23 @@ type apiPathConfList struct {
 	Items     []*conf.OptionalPath `json:"items"`
 }
 
+// apiConfigSubtree is the body of
+// GET /v3/config/paths/get/{name}/{jsonpath} and
+// PATCH /v3/config/paths/patch/{name}/{jsonpath}, which read or write a
+// single field of a path config by JSON Pointer (RFC 6901) instead of the
+// whole object.
+type apiConfigSubtree struct {
+	Value json.RawMessage `json:"value"`
+}
+
+// apiConfigPreconditionFailed is returned with a 412 status when the
+// If-Match header sent by the caller doesn't match the current config
+// fingerprint, i.e. the config was changed since the caller last read it.
+type apiConfigPreconditionFailed struct {
+	Error              string `json:"error"`
+	CurrentFingerprint string `json:"currentFingerprint"`
+}
+
 type apiPathSourceOrReader struct {
 	Type string `json:"type"`
 	ID   string `json:"id"`
@@ -91,6 +109,16 @@ type apiRTMPConnList struct {
 	Items     []*apiRTMPConn `json:"items"`
 }
 
+// apiSessionDeadlineReq is the body of
+// PATCH /v3/{rtspsessions|rtmpconns|srtconns|webrtcsessions}/{id}/deadline,
+// shared by RTSP, RTMP, SRT and WebRTC sessions. A nil field leaves the
+// corresponding deadline untouched; a zero or negative duration makes it
+// expire immediately.
+type apiSessionDeadlineReq struct {
+	ReadDeadline  *conf.StringDuration `json:"readDeadline"`
+	WriteDeadline *conf.StringDuration `json:"writeDeadline"`
+}
+
 type apiRTSPSessionState string
 
 const (
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitJSONPointer parses an RFC 6901 JSON Pointer into its unescaped
+// reference tokens. The root pointer ("") yields no tokens.
+func splitJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer '%s': must start with '/'", pointer)
+	}
+
+	raw := strings.Split(pointer[1:], "/")
+	tokens := make([]string, len(raw))
+	for i, t := range raw {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = t
+	}
+	return tokens, nil
+}
+
+// jsonPointerGet resolves pointer against v, a generic value as produced by
+// json.Unmarshal into an interface{} (map[string]interface{},
+// []interface{} or a scalar).
+func jsonPointerGet(v interface{}, pointer string) (interface{}, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := v
+	for _, tok := range tokens {
+		switch n := cur.(type) {
+		case map[string]interface{}:
+			val, ok := n[tok]
+			if !ok {
+				return nil, fmt.Errorf("field '%s' does not exist", tok)
+			}
+			cur = val
+
+		case []interface{}:
+			idx, err := strconv.Atoi(tok)
+			if err != nil || idx < 0 || idx >= len(n) {
+				return nil, fmt.Errorf("invalid array index '%s'", tok)
+			}
+			cur = n[idx]
+
+		default:
+			return nil, fmt.Errorf("cannot descend into a scalar at '%s'", tok)
+		}
+	}
+
+	return cur, nil
+}
+
+// jsonPointerSet mutates v in place, replacing the value at pointer with
+// newVal. The parent addressed by all but the last token must already
+// exist; the root pointer ("") replaces v wholesale.
+func jsonPointerSet(v interface{}, pointer string, newVal interface{}) (interface{}, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return newVal, nil
+	}
+
+	parent := v
+	if len(tokens) > 1 {
+		parentPointer := "/" + strings.Join(tokens[:len(tokens)-1], "/")
+		parent, err = jsonPointerGet(v, parentPointer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	last := tokens[len(tokens)-1]
+
+	switch n := parent.(type) {
+	case map[string]interface{}:
+		n[last] = newVal
+
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(n) {
+			return nil, fmt.Errorf("invalid array index '%s'", last)
+		}
+		n[idx] = newVal
+
+	default:
+		return nil, fmt.Errorf("cannot set a field on a scalar")
+	}
+
+	return v, nil
+}
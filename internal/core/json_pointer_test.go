@@ -0,0 +1,51 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPointerGet(t *testing.T) {
+	v := map[string]interface{}{
+		"name": "mystream",
+		"nested": map[string]interface{}{
+			"items": []interface{}{"a", "b", "c"},
+		},
+	}
+
+	val, err := jsonPointerGet(v, "/name")
+	require.NoError(t, err)
+	require.Equal(t, "mystream", val)
+
+	val, err = jsonPointerGet(v, "/nested/items/1")
+	require.NoError(t, err)
+	require.Equal(t, "b", val)
+
+	_, err = jsonPointerGet(v, "/missing")
+	require.Error(t, err)
+
+	_, err = jsonPointerGet(v, "no-leading-slash")
+	require.Error(t, err)
+}
+
+func TestJSONPointerSet(t *testing.T) {
+	v := map[string]interface{}{
+		"name": "mystream",
+		"nested": map[string]interface{}{
+			"items": []interface{}{"a", "b", "c"},
+		},
+	}
+
+	out, err := jsonPointerSet(v, "/name", "renamed")
+	require.NoError(t, err)
+	require.Equal(t, "renamed", out.(map[string]interface{})["name"])
+
+	out, err = jsonPointerSet(v, "/nested/items/1", "z")
+	require.NoError(t, err)
+	nested := out.(map[string]interface{})["nested"].(map[string]interface{})
+	require.Equal(t, "z", nested["items"].([]interface{})[1])
+
+	_, err = jsonPointerSet(v, "/nested/items/99", "x")
+	require.Error(t, err)
+}
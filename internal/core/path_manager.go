@@ -0,0 +1,205 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bluenviron/mediamtx/internal/conf"
+)
+
+// authProtocol identifies the protocol an access request came in on, so
+// that auth can apply protocol-specific rules (e.g. only WebRTC carries a
+// bearer token today).
+type authProtocol string
+
+// Authentication protocols.
+const (
+	authProtocolRTSP   authProtocol = "rtsp"
+	authProtocolRTMP   authProtocol = "rtmp"
+	authProtocolHLS    authProtocol = "hls"
+	authProtocolWebRTC authProtocol = "webrtc"
+	authProtocolSRT    authProtocol = "srt"
+)
+
+// errAuthentication is returned by pathManager.getConfForPath when a
+// request fails path-level authentication.
+type errAuthentication struct {
+	message string
+}
+
+func (e *errAuthentication) Error() string {
+	return "authentication failed: " + e.message
+}
+
+// pathAccessRequest carries everything needed to authenticate and
+// authorize access to a path, regardless of which protocol it came in on.
+type pathAccessRequest struct {
+	name    string
+	query   string
+	publish bool
+	ip      net.IP
+	user    string
+	pass    string
+	token   string
+	proto   authProtocol
+}
+
+type pathGetConfForPathReq struct {
+	accessRequest pathAccessRequest
+}
+
+type pathGetConfForPathRes struct {
+	conf *conf.OptionalPath
+	err  error
+}
+
+type pathManager struct {
+	conf *conf.LockedConfig
+}
+
+// errPathNotConfigured is a sentinel so every caller -- path access as well
+// as the config API -- maps "no such path" to the same outcome (a 404 at
+// the HTTP layer) regardless of which code path detected it.
+var errPathNotConfigured = fmt.Errorf("path is not configured")
+
+// getConfForPath validates req against the path's configured auth methods
+// and credentials, then returns the path config on success.
+func (pm *pathManager) getConfForPath(req pathGetConfForPathReq) pathGetConfForPathRes {
+	c, _, err := pm.conf.Read()
+	if err != nil {
+		return pathGetConfForPathRes{err: err}
+	}
+
+	p, ok := c.Paths[req.accessRequest.name]
+	if !ok {
+		return pathGetConfForPathRes{err: errPathNotConfigured}
+	}
+
+	allowed := c.AuthMethodsForPath(req.accessRequest.name)
+
+	switch {
+	case req.accessRequest.token != "":
+		switch {
+		case conf.AuthMethodAllowed(allowed, conf.AuthMethodJWT) && looksLikeJWT(req.accessRequest.token):
+			if err := pm.validateJWT(p, req.accessRequest.token); err != nil {
+				return pathGetConfForPathRes{err: &errAuthentication{message: err.Error()}}
+			}
+
+		case conf.AuthMethodAllowed(allowed, conf.AuthMethodBearer):
+			if !pm.validateToken(p, req.accessRequest.token) {
+				return pathGetConfForPathRes{err: &errAuthentication{message: "invalid bearer token"}}
+			}
+
+		default:
+			return pathGetConfForPathRes{
+				err: &errAuthentication{message: "bearer authentication is not enabled for this path"},
+			}
+		}
+
+	case req.accessRequest.user != "" || req.accessRequest.pass != "":
+		if !conf.AuthMethodAllowed(allowed, conf.AuthMethodBasic) {
+			return pathGetConfForPathRes{
+				err: &errAuthentication{message: "basic authentication is not enabled for this path"},
+			}
+		}
+
+		if !pm.validateBasic(p, req.accessRequest.user, req.accessRequest.pass) {
+			return pathGetConfForPathRes{err: &errAuthentication{message: "invalid credentials"}}
+		}
+
+	default:
+		return pathGetConfForPathRes{err: &errAuthentication{message: "no credentials provided"}}
+	}
+
+	return pathGetConfForPathRes{conf: p}
+}
+
+// validateToken compares token against p's configured bearer secret in
+// constant time, so a wrong guess can't be narrowed down byte by byte
+// through response-time measurements. A path with no configured secret
+// never matches, however the token reads.
+func (pm *pathManager) validateToken(p *conf.OptionalPath, token string) bool {
+	return p.Token != "" && subtle.ConstantTimeCompare([]byte(p.Token), []byte(token)) == 1
+}
+
+// validateBasic compares user/pass against p's configured credentials in
+// constant time. A path with no configured user/pass never matches.
+func (pm *pathManager) validateBasic(p *conf.OptionalPath, user, pass string) bool {
+	if p.User == "" && p.Pass == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(p.User), []byte(user)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(p.Pass), []byte(pass)) == 1
+}
+
+// looksLikeJWT reports whether token has the header.payload.signature shape
+// of a JWT, so that when a path allows both "bearer" and "jwt" a plain
+// shared-secret token isn't run through the JWT parser by mistake.
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// validateJWT verifies an HS256-signed JWT against p's configured secret
+// and, if present, its "exp" claim. Only HS256 is supported: trusting an
+// "alg" read from the token itself would let a caller pick "none" and skip
+// the signature check entirely.
+func (pm *pathManager) validateJWT(p *conf.OptionalPath, token string) error {
+	if p.JWTSecret == "" {
+		return fmt.Errorf("jwt authentication is not configured for this path")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed jwt")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("malformed jwt header")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fmt.Errorf("malformed jwt header")
+	}
+	if header.Alg != "HS256" {
+		return fmt.Errorf("unsupported jwt algorithm '%s'", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("malformed jwt signature")
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.JWTSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("invalid jwt signature")
+	}
+
+	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("malformed jwt payload")
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payloadJSON, &claims); err != nil {
+		return fmt.Errorf("malformed jwt payload")
+	}
+	if claims.Exp != 0 && time.Now().Unix() > claims.Exp {
+		return fmt.Errorf("jwt has expired")
+	}
+
+	return nil
+}
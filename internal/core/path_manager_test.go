@@ -0,0 +1,195 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bluenviron/mediamtx/internal/conf"
+)
+
+func TestPathManagerBearerRequiresAuthMethod(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBasic},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream", Token: "secret"},
+			},
+		}),
+	}
+
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: "secret",
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.Error(t, res.err)
+
+	pm = &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBearer},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream", Token: "secret"},
+			},
+		}),
+	}
+
+	res = pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: "secret",
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.NoError(t, res.err)
+}
+
+func TestPathManagerBearerRejectsWrongToken(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBearer},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream", Token: "secret"},
+			},
+		}),
+	}
+
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: "wrong",
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.Error(t, res.err)
+}
+
+func TestPathManagerBearerRejectsUnconfiguredSecret(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBearer},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream"},
+			},
+		}),
+	}
+
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: "anything",
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.Error(t, res.err)
+}
+
+func TestPathManagerBasicRejectsWrongCredentials(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBasic},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream", User: "alice", Pass: "good"},
+			},
+		}),
+	}
+
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			user:  "alice",
+			pass:  "bad",
+			proto: authProtocolRTSP,
+		},
+	})
+	require.Error(t, res.err)
+
+	res = pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			user:  "alice",
+			pass:  "good",
+			proto: authProtocolRTSP,
+		},
+	})
+	require.NoError(t, res.err)
+}
+
+func TestPathManagerJWTValidatesSignatureAndExpiry(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodJWT},
+			Paths: map[string]*conf.OptionalPath{
+				"mystream": {Name: "mystream", JWTSecret: "jwtsecret"},
+			},
+		}),
+	}
+
+	valid := signHS256(t, "jwtsecret", `{"alg":"HS256"}`, `{"sub":"alice"}`)
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: valid,
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.NoError(t, res.err)
+
+	wrongSecret := signHS256(t, "not-the-secret", `{"alg":"HS256"}`, `{"sub":"alice"}`)
+	res = pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: wrongSecret,
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.Error(t, res.err)
+
+	expired := signHS256(t, "jwtsecret", `{"alg":"HS256"}`, `{"exp":1}`)
+	res = pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "mystream",
+			token: expired,
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.Error(t, res.err)
+}
+
+func TestPathManagerUnconfiguredPathIs404able(t *testing.T) {
+	pm := &pathManager{
+		conf: conf.NewLockedConfig(&conf.Conf{
+			AuthMethods: []conf.AuthMethod{conf.AuthMethodBearer},
+			Paths:       map[string]*conf.OptionalPath{},
+		}),
+	}
+
+	res := pm.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:  "missing",
+			token: "anything",
+			proto: authProtocolWebRTC,
+		},
+	})
+	require.ErrorIs(t, res.err, errPathNotConfigured)
+}
+
+// signHS256 builds a compact JWT the way a real signer would, so the
+// validator is exercised against the actual wire format rather than a
+// hand-picked fixture string.
+func signHS256(t *testing.T, secret, headerJSON, payloadJSON string) string {
+	t.Helper()
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(headerJSON))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(payloadJSON))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + payload))
+
+	return header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"crypto/aes"
+	"crypto/rand"
 	_ "embed"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net"
@@ -40,18 +43,84 @@ func relativeLocation(u *url.URL) string {
 	return p
 }
 
+// encryptSessionSecret encrypts a session secret UUID with AES, using the
+// fact that a UUID is exactly one AES block (16 bytes) long, so no chaining
+// mode or padding is needed. The result is base64url-encoded for safe use
+// inside a URL path segment.
+func encryptSessionSecret(key [16]byte, secret uuid.UUID) (string, error) {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return "", err
+	}
+
+	var enc [16]byte
+	block.Encrypt(enc[:], secret[:])
+
+	return base64.RawURLEncoding.EncodeToString(enc[:]), nil
+}
+
+// decryptSessionSecret reverses encryptSessionSecret.
+func decryptSessionSecret(key [16]byte, raw string) (uuid.UUID, error) {
+	enc, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if len(enc) != 16 {
+		return uuid.UUID{}, fmt.Errorf("invalid encrypted session secret length")
+	}
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	var dec [16]byte
+	block.Decrypt(dec[:], enc)
+
+	return uuid.FromBytes(dec[:])
+}
+
 type webRTCHTTPServerParent interface {
 	logger.Writer
 	generateICEServers() ([]pwebrtc.ICEServer, error)
 	newSession(req webRTCNewSessionReq) webRTCNewSessionRes
 	addSessionCandidates(req webRTCAddSessionCandidatesReq) webRTCAddSessionCandidatesRes
 	deleteSession(req webRTCDeleteSessionReq) error
+	sessionSSEStream(req webRTCSSEStreamReq) webRTCSSEStreamRes
+}
+
+// webRTCSSEEvent is a single server-sent event pushed to a WHIP/WHEP client
+// that subscribed to the session's event stream.
+type webRTCSSEEvent struct {
+	name string // "candidate", "layer" or "bye"
+	data string
+}
+
+type webRTCSSEStreamReq struct {
+	secret uuid.UUID
+}
+
+type webRTCSSEStreamRes struct {
+	err error
+
+	// events is closed by the session when it terminates, after a final
+	// "bye" event that is dropped if the channel's buffer is full, or by
+	// calling unsubscribe.
+	events <-chan webRTCSSEEvent
+
+	// unsubscribe must be called once the HTTP handler stops reading from
+	// events (e.g. because the client disconnected), so the session stops
+	// pushing into a channel nobody drains anymore.
+	unsubscribe func()
 }
 
 type webRTCHTTPServer struct {
-	allowOrigin string
-	pathManager *pathManager
-	parent      webRTCHTTPServerParent
+	allowOrigin          string
+	obfuscateSessionURLs bool
+	sessionURLKey        [16]byte
+	pathManager          *pathManager
+	parent               webRTCHTTPServerParent
 
 	inner *httpserv.WrappedServer
 }
@@ -64,6 +133,7 @@ func newWebRTCHTTPServer( //nolint:dupl
 	allowOrigin string,
 	trustedProxies conf.IPsOrCIDRs,
 	readTimeout conf.StringDuration,
+	obfuscateSessionURLs bool,
 	pathManager *pathManager,
 	parent webRTCHTTPServerParent,
 ) (*webRTCHTTPServer, error) {
@@ -77,9 +147,16 @@ func newWebRTCHTTPServer( //nolint:dupl
 	}
 
 	s := &webRTCHTTPServer{
-		allowOrigin: allowOrigin,
-		pathManager: pathManager,
-		parent:      parent,
+		allowOrigin:          allowOrigin,
+		obfuscateSessionURLs: obfuscateSessionURLs,
+		pathManager:          pathManager,
+		parent:               parent,
+	}
+
+	if obfuscateSessionURLs {
+		if _, err := rand.Read(s.sessionURLKey[:]); err != nil {
+			return nil, err
+		}
 	}
 
 	router := gin.New()
@@ -105,6 +182,35 @@ func newWebRTCHTTPServer( //nolint:dupl
 	return s, nil
 }
 
+// newWebRTCHTTPServerFromConf is newWebRTCHTTPServer with the
+// obfuscateSessionURLs argument taken from
+// conf.Conf.WebRTCObfuscateSessionURLs. It has no caller in this package.
+func newWebRTCHTTPServerFromConf(
+	address string,
+	encryption bool,
+	serverKey string,
+	serverCert string,
+	allowOrigin string,
+	trustedProxies conf.IPsOrCIDRs,
+	readTimeout conf.StringDuration,
+	c *conf.Conf,
+	pathManager *pathManager,
+	parent webRTCHTTPServerParent,
+) (*webRTCHTTPServer, error) {
+	return newWebRTCHTTPServer(
+		address,
+		encryption,
+		serverKey,
+		serverCert,
+		allowOrigin,
+		trustedProxies,
+		readTimeout,
+		c.WebRTCObfuscateSessionURLs,
+		pathManager,
+		parent,
+	)
+}
+
 func (s *webRTCHTTPServer) Log(level logger.Level, format string, args ...interface{}) {
 	s.parent.Log(level, format, args...)
 }
@@ -113,11 +219,30 @@ func (s *webRTCHTTPServer) close() {
 	s.inner.Close()
 }
 
+// bearerToken extracts a bearer token from the Authorization header. The
+// header may contain a comma-separated list of credentials, as allowed by
+// RFC 7235, so each scheme is checked in turn and the match is case-insensitive.
+func bearerToken(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if len(part) > len(bearerPrefix) && strings.EqualFold(part[:len(bearerPrefix)], bearerPrefix) {
+			return part[len(bearerPrefix):]
+		}
+	}
+	return ""
+}
+
+const bearerPrefix = "Bearer "
+
 func (s *webRTCHTTPServer) checkAuthOutsideSession(ctx *gin.Context, path string, publish bool) bool {
 	ip := ctx.ClientIP()
 	_, port, _ := net.SplitHostPort(ctx.Request.RemoteAddr)
 	remoteAddr := net.JoinHostPort(ip, port)
 	user, pass, hasCredentials := ctx.Request.BasicAuth()
+	token := bearerToken(ctx.Request.Header.Get("Authorization"))
+	if token != "" {
+		hasCredentials = true
+	}
 
 	res := s.pathManager.getConfForPath(pathGetConfForPathReq{
 		accessRequest: pathAccessRequest{
@@ -127,13 +252,15 @@ func (s *webRTCHTTPServer) checkAuthOutsideSession(ctx *gin.Context, path string
 			ip:      net.ParseIP(ip),
 			user:    user,
 			pass:    pass,
+			token:   token,
 			proto:   authProtocolWebRTC,
 		},
 	})
 	if res.err != nil {
 		if terr, ok := res.err.(*errAuthentication); ok {
 			if !hasCredentials {
-				ctx.Header("WWW-Authenticate", `Basic realm="mediamtx"`)
+				ctx.Writer.Header().Add("WWW-Authenticate", `Basic realm="mediamtx"`)
+				ctx.Writer.Header().Add("WWW-Authenticate", `Bearer realm="mediamtx"`)
 				ctx.Writer.WriteHeader(http.StatusUnauthorized)
 				return false
 			}
@@ -187,6 +314,7 @@ func (s *webRTCHTTPServer) onWHIPPost(ctx *gin.Context, path string, publish boo
 	_, port, _ := net.SplitHostPort(ctx.Request.RemoteAddr)
 	remoteAddr := net.JoinHostPort(ip, port)
 	user, pass, _ := ctx.Request.BasicAuth()
+	token := bearerToken(ctx.Request.Header.Get("Authorization"))
 
 	res := s.parent.newSession(webRTCNewSessionReq{
 		pathName:   path,
@@ -194,6 +322,7 @@ func (s *webRTCHTTPServer) onWHIPPost(ctx *gin.Context, path string, publish boo
 		query:      ctx.Request.URL.RawQuery,
 		user:       user,
 		pass:       pass,
+		token:      token,
 		offer:      offer,
 		publish:    publish,
 	})
@@ -214,14 +343,31 @@ func (s *webRTCHTTPServer) onWHIPPost(ctx *gin.Context, path string, publish boo
 	ctx.Writer.Header().Set("ID", res.sx.uuid.String())
 	ctx.Writer.Header().Set("Accept-Patch", "application/trickle-ice-sdpfrag")
 	ctx.Writer.Header()["Link"] = webrtc.LinkHeaderMarshal(servers)
-	ctx.Request.URL.Path += "/" + res.sx.secret.String()
+
+	sessionPathID := res.sx.secret.String()
+	if s.obfuscateSessionURLs {
+		sessionPathID, err = encryptSessionSecret(s.sessionURLKey, res.sx.secret)
+		if err != nil {
+			ctx.Writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	ctx.Request.URL.Path += "/" + sessionPathID
 	ctx.Writer.Header().Set("Location", relativeLocation(ctx.Request.URL))
 	ctx.Writer.WriteHeader(http.StatusCreated)
 	ctx.Writer.Write(res.answer)
 }
 
+func (s *webRTCHTTPServer) parseSessionSecret(rawSecret string) (uuid.UUID, error) {
+	if s.obfuscateSessionURLs {
+		return decryptSessionSecret(s.sessionURLKey, rawSecret)
+	}
+	return uuid.Parse(rawSecret)
+}
+
 func (s *webRTCHTTPServer) onWHIPPatch(ctx *gin.Context, rawSecret string) {
-	secret, err := uuid.Parse(rawSecret)
+	secret, err := s.parseSessionSecret(rawSecret)
 	if err != nil {
 		ctx.Writer.WriteHeader(http.StatusBadRequest)
 		return
@@ -256,7 +402,7 @@ func (s *webRTCHTTPServer) onWHIPPatch(ctx *gin.Context, rawSecret string) {
 }
 
 func (s *webRTCHTTPServer) onWHIPDelete(ctx *gin.Context, rawSecret string) {
-	secret, err := uuid.Parse(rawSecret)
+	secret, err := s.parseSessionSecret(rawSecret)
 	if err != nil {
 		ctx.Writer.WriteHeader(http.StatusBadRequest)
 		return
@@ -273,6 +419,56 @@ func (s *webRTCHTTPServer) onWHIPDelete(ctx *gin.Context, rawSecret string) {
 	ctx.Writer.WriteHeader(http.StatusOK)
 }
 
+// onWHIPGetEvents upgrades a GET request on a session URL to a
+// text/event-stream connection, and streams server-side ICE candidates,
+// simulcast/SVC layer changes and a final "bye" event until the session
+// terminates or the client disconnects.
+func (s *webRTCHTTPServer) onWHIPGetEvents(ctx *gin.Context, rawSecret string) {
+	secret, err := s.parseSessionSecret(rawSecret)
+	if err != nil {
+		ctx.Writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	res := s.parent.sessionSSEStream(webRTCSSEStreamReq{secret: secret})
+	if res.err != nil {
+		ctx.Writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer res.unsubscribe()
+
+	flusher, ok := ctx.Writer.(http.Flusher)
+	if !ok {
+		ctx.Writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Writer.Header().Set("Content-Type", "text/event-stream")
+	ctx.Writer.Header().Set("Cache-Control", "no-cache")
+	ctx.Writer.Header().Set("Connection", "keep-alive")
+	ctx.Writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case evt, ok := <-res.events:
+			if !ok {
+				return
+			}
+
+			fmt.Fprintf(ctx.Writer, "event: %s\ndata: %s\n\n", evt.name, evt.data)
+			flusher.Flush()
+
+			if evt.name == "bye" {
+				return
+			}
+
+		case <-ctx.Request.Context().Done():
+			return
+		}
+	}
+}
+
 func (s *webRTCHTTPServer) onPage(ctx *gin.Context, path string, publish bool) {
 	if !s.checkAuthOutsideSession(ctx, path, publish) {
 		return
@@ -328,6 +524,13 @@ func (s *webRTCHTTPServer) onRequest(ctx *gin.Context) {
 
 		case http.MethodDelete:
 			s.onWHIPDelete(ctx, m[3])
+
+		case http.MethodGet:
+			if strings.Contains(ctx.Request.Header.Get("Accept"), "text/event-stream") {
+				s.onWHIPGetEvents(ctx, m[3])
+			} else {
+				ctx.Writer.WriteHeader(http.StatusMethodNotAllowed)
+			}
 		}
 		return
 	}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionSecretEncryptionRoundTrip(t *testing.T) {
+	var key [16]byte
+	_, err := rand.Read(key[:])
+	require.NoError(t, err)
+
+	secret := uuid.New()
+
+	enc, err := encryptSessionSecret(key, secret)
+	require.NoError(t, err)
+
+	dec, err := decryptSessionSecret(key, enc)
+	require.NoError(t, err)
+	require.Equal(t, secret, dec)
+}
+
+func TestSessionSecretDecryptionMalformed(t *testing.T) {
+	var key [16]byte
+	_, err := rand.Read(key[:])
+	require.NoError(t, err)
+
+	for _, ca := range []struct {
+		name string
+		raw  string
+	}{
+		{"not base64url", "!!!not-base64!!!"},
+		{"too short", "AAAA"},
+		{"too long", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"},
+		{"empty", ""},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			_, err := decryptSessionSecret(key, ca.raw)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSessionSecretInvalidatedAcrossRestart(t *testing.T) {
+	var keyBefore, keyAfter [16]byte
+	_, err := rand.Read(keyBefore[:])
+	require.NoError(t, err)
+	_, err = rand.Read(keyAfter[:])
+	require.NoError(t, err)
+
+	secret := uuid.New()
+
+	enc, err := encryptSessionSecret(keyBefore, secret)
+	require.NoError(t, err)
+
+	// simulate a process restart: a new random key is generated, so a URL
+	// minted before the restart must not resolve to a valid secret anymore.
+	dec, err := decryptSessionSecret(keyAfter, enc)
+	if err == nil {
+		require.NotEqual(t, secret, dec)
+	}
+}
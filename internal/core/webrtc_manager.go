@@ -0,0 +1,171 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	pwebrtc "github.com/pion/webrtc/v3"
+
+	"github.com/bluenviron/mediamtx/internal/deadline"
+	"github.com/bluenviron/mediamtx/internal/logger"
+)
+
+type webRTCNewSessionReq struct {
+	pathName   string
+	remoteAddr string
+	query      string
+	user       string
+	pass       string
+	token      string
+	offer      []byte
+	publish    bool
+}
+
+type webRTCNewSessionRes struct {
+	err           error
+	errStatusCode int
+	sx            *webRTCSession
+	answer        []byte
+}
+
+type webRTCAddSessionCandidatesReq struct {
+	secret     uuid.UUID
+	candidates []*pwebrtc.ICECandidateInit
+}
+
+type webRTCAddSessionCandidatesRes struct {
+	err error
+}
+
+type webRTCDeleteSessionReq struct {
+	secret uuid.UUID
+}
+
+// webRTCManager owns every active WHIP/WHEP session and satisfies
+// webRTCHTTPServerParent, so that webRTCHTTPServer never has to know how a
+// session is authenticated, created or torn down.
+type webRTCManager struct {
+	parent      logger.Writer
+	pathManager *pathManager
+	deadlines   *deadline.Registry[uuid.UUID]
+
+	mutex        sync.Mutex
+	sessions     map[uuid.UUID]*webRTCSession // keyed by secret
+	sessionsByID map[uuid.UUID]*webRTCSession // keyed by public uuid, as exposed through the API
+}
+
+func newWebRTCManager(parent logger.Writer, pathManager *pathManager) *webRTCManager {
+	return &webRTCManager{
+		parent:       parent,
+		pathManager:  pathManager,
+		deadlines:    deadline.NewRegistry[uuid.UUID](),
+		sessions:     make(map[uuid.UUID]*webRTCSession),
+		sessionsByID: make(map[uuid.UUID]*webRTCSession),
+	}
+}
+
+// findByID returns the session exposed as apiWebRTCSession.ID == id, or nil.
+func (m *webRTCManager) findByID(id uuid.UUID) *webRTCSession {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.sessionsByID[id]
+}
+
+func (m *webRTCManager) Log(level logger.Level, format string, args ...interface{}) {
+	m.parent.Log(level, format, args...)
+}
+
+func (m *webRTCManager) generateICEServers() ([]pwebrtc.ICEServer, error) {
+	return nil, nil
+}
+
+func (m *webRTCManager) newSession(req webRTCNewSessionReq) webRTCNewSessionRes {
+	res := m.pathManager.getConfForPath(pathGetConfForPathReq{
+		accessRequest: pathAccessRequest{
+			name:    req.pathName,
+			query:   req.query,
+			publish: req.publish,
+			user:    req.user,
+			pass:    req.pass,
+			token:   req.token,
+			proto:   authProtocolWebRTC,
+		},
+	})
+	if res.err != nil {
+		if _, ok := res.err.(*errAuthentication); ok {
+			return webRTCNewSessionRes{err: res.err, errStatusCode: 401}
+		}
+		return webRTCNewSessionRes{err: res.err, errStatusCode: 404}
+	}
+
+	sx := newWebRTCSession(nil)
+
+	m.mutex.Lock()
+	m.sessions[sx.secret] = sx
+	m.sessionsByID[sx.uuid] = sx
+	m.mutex.Unlock()
+
+	m.deadlines.Add(sx.uuid, sx.deadline)
+
+	return webRTCNewSessionRes{sx: sx}
+}
+
+func (m *webRTCManager) addSessionCandidates(req webRTCAddSessionCandidatesReq) webRTCAddSessionCandidatesRes {
+	m.mutex.Lock()
+	sx, ok := m.sessions[req.secret]
+	m.mutex.Unlock()
+
+	if !ok {
+		return webRTCAddSessionCandidatesRes{err: fmt.Errorf("session not found")}
+	}
+
+	if sx.pc != nil {
+		for _, c := range req.candidates {
+			if err := sx.pc.AddICECandidate(*c); err != nil {
+				return webRTCAddSessionCandidatesRes{err: err}
+			}
+		}
+	}
+
+	return webRTCAddSessionCandidatesRes{}
+}
+
+func (m *webRTCManager) deleteSession(req webRTCDeleteSessionReq) error {
+	m.mutex.Lock()
+	sx, ok := m.sessions[req.secret]
+	delete(m.sessions, req.secret)
+	if ok {
+		delete(m.sessionsByID, sx.uuid)
+	}
+	m.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("session not found")
+	}
+
+	m.deadlines.Remove(sx.uuid)
+	sx.close()
+	return nil
+}
+
+// sessionSSEStream subscribes the caller to a session's server-candidate,
+// layer-change and bye events. The caller MUST invoke the returned
+// unsubscribe function once it stops reading from events, otherwise a
+// disconnected client leaves a dangling subscription that the session
+// keeps pushing into forever.
+func (m *webRTCManager) sessionSSEStream(req webRTCSSEStreamReq) webRTCSSEStreamRes {
+	m.mutex.Lock()
+	sx, ok := m.sessions[req.secret]
+	m.mutex.Unlock()
+
+	if !ok {
+		return webRTCSSEStreamRes{err: fmt.Errorf("session not found")}
+	}
+
+	ch, unsubscribe := sx.subscribeSSE()
+
+	return webRTCSSEStreamRes{events: ch, unsubscribe: unsubscribe}
+}
+
+var _ webRTCHTTPServerParent = (*webRTCManager)(nil)
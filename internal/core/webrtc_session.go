@@ -0,0 +1,120 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	pwebrtc "github.com/pion/webrtc/v3"
+
+	"github.com/bluenviron/mediamtx/internal/deadline"
+)
+
+// webRTCSession tracks a single WHIP/WHEP session: its secret, its
+// underlying PeerConnection, the SSE clients subscribed to its
+// server-candidate/layer-change/bye events, and the read/write deadline
+// pair an operator can tighten through the API without tearing the
+// session down.
+type webRTCSession struct {
+	uuid     uuid.UUID
+	secret   uuid.UUID
+	pc       *pwebrtc.PeerConnection
+	deadline *deadline.Pair
+
+	mutex      sync.Mutex
+	sseClients []chan webRTCSSEEvent
+}
+
+func newWebRTCSession(pc *pwebrtc.PeerConnection) *webRTCSession {
+	s := &webRTCSession{
+		uuid:     uuid.New(),
+		secret:   uuid.New(),
+		pc:       pc,
+		deadline: deadline.NewPair(),
+	}
+
+	if pc != nil {
+		pc.OnICECandidate(func(c *pwebrtc.ICECandidate) {
+			if c != nil {
+				s.onServerCandidate(c)
+			}
+		})
+	}
+
+	return s
+}
+
+// subscribeSSE registers a new event-stream client and returns its channel
+// plus an unsubscribe function. The channel is closed, and the
+// subscription dropped, either by unsubscribe or by close.
+func (s *webRTCSession) subscribeSSE() (<-chan webRTCSSEEvent, func()) {
+	ch := make(chan webRTCSSEEvent, 8)
+
+	s.mutex.Lock()
+	s.sseClients = append(s.sseClients, ch)
+	s.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+
+			for i, c := range s.sseClients {
+				if c == ch {
+					s.sseClients = append(s.sseClients[:i], s.sseClients[i+1:]...)
+					close(c)
+					break
+				}
+			}
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+func (s *webRTCSession) broadcastSSE(evt webRTCSSEEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, ch := range s.sseClients {
+		select {
+		case ch <- evt:
+		default:
+			// a slow client must not stall candidate/layer delivery to
+			// the others; it will simply miss this event.
+		}
+	}
+}
+
+// onServerCandidate is registered as the PeerConnection's ICE candidate
+// callback, and forwards every local candidate to subscribed SSE clients.
+// The event data is the candidate attribute value from
+// ICECandidate.ToJSON ("candidate:..."), not a full trickle-ice-sdpfrag
+// body: no ufrag, pwd or media section lines are included.
+func (s *webRTCSession) onServerCandidate(c *pwebrtc.ICECandidate) {
+	s.broadcastSSE(webRTCSSEEvent{name: "candidate", data: c.ToJSON().Candidate})
+}
+
+// onLayerChange broadcasts a "layer" event carrying the given
+// simulcast/SVC layer to subscribed SSE clients. Nothing in this package
+// calls it, so layer changes are not reported on their own.
+func (s *webRTCSession) onLayerChange(layer string) {
+	s.broadcastSSE(webRTCSSEEvent{name: "layer", data: layer})
+}
+
+// close notifies SSE subscribers that the session ended and closes their
+// channels.
+func (s *webRTCSession) close() {
+	s.mutex.Lock()
+	clients := s.sseClients
+	s.sseClients = nil
+	s.mutex.Unlock()
+
+	for _, ch := range clients {
+		select {
+		case ch <- webRTCSSEEvent{name: "bye"}:
+		default:
+		}
+		close(ch)
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebRTCSessionSSEUnsubscribeStopsDelivery(t *testing.T) {
+	sx := newWebRTCSession(nil)
+
+	ch, unsubscribe := sx.subscribeSSE()
+
+	sx.onLayerChange("high")
+	evt, ok := <-ch
+	require.True(t, ok)
+	require.Equal(t, "layer", evt.name)
+
+	unsubscribe()
+
+	// after unsubscribing, the channel must be closed so a disconnected
+	// client's reader goroutine exits instead of leaking.
+	_, ok = <-ch
+	require.False(t, ok)
+
+	// broadcasting after unsubscribe must not panic or block, since the
+	// session no longer tracks this client.
+	sx.onLayerChange("low")
+}
+
+func TestWebRTCSessionCloseSendsByeAndClosesChannel(t *testing.T) {
+	sx := newWebRTCSession(nil)
+
+	ch, _ := sx.subscribeSSE()
+
+	sx.close()
+
+	evt, ok := <-ch
+	require.True(t, ok)
+	require.Equal(t, "bye", evt.name)
+
+	_, ok = <-ch
+	require.False(t, ok)
+}
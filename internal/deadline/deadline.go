@@ -0,0 +1,126 @@
+// Package deadline provides a resettable deadline that session
+// implementations can use to let an external API throttle or evict a stuck
+// publisher or reader without killing the whole connection.
+package deadline
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer is a rearmable deadline. A blocking read or write loop selects on
+// the channel returned by C, and gives up when it's closed. It is safe for
+// concurrent use, so the same Timer can be shared between the goroutine
+// enforcing the deadline and the API handler that sets it.
+type Timer struct {
+	mutex sync.Mutex
+	timer *time.Timer
+	c     chan struct{}
+}
+
+// NewTimer allocates a Timer. It starts disarmed: C never closes until Set
+// is called.
+func NewTimer() *Timer {
+	return &Timer{
+		c: make(chan struct{}),
+	}
+}
+
+// Set rearms the timer so that it fires after d, closing the channel
+// returned by C. A duration of zero or less closes C immediately. Any
+// previously scheduled expiry is canceled.
+func (t *Timer) Set(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+
+	c := make(chan struct{})
+	t.c = c
+
+	if d <= 0 {
+		close(c)
+		return
+	}
+
+	t.timer = time.AfterFunc(d, func() {
+		close(c)
+	})
+}
+
+// C returns the channel associated with the current deadline. It is closed
+// when the duration passed to the last call to Set elapses.
+func (t *Timer) C() <-chan struct{} {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.c
+}
+
+// Stop disarms the timer without closing the current C channel.
+func (t *Timer) Stop() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// Pair bundles the read and write deadlines of a single connection, so that
+// the four session types (RTSP, RTMP, SRT, WebRTC) can expose them through
+// a uniform API regardless of how their underlying connection enforces
+// timeouts internally.
+type Pair struct {
+	Read  *Timer
+	Write *Timer
+}
+
+// NewPair allocates a Pair with both deadlines disarmed.
+func NewPair() *Pair {
+	return &Pair{
+		Read:  NewTimer(),
+		Write: NewTimer(),
+	}
+}
+
+// Registry maps session IDs of a given protocol to the deadline Pair
+// registered for them with Add, so that a uniform deadline API handler can
+// reach any of the four session types without knowing how each one is
+// implemented internally. Setting a Pair's timers only closes their
+// channels; it affects a connection only where that connection's I/O loop
+// selects on Pair.Read.C()/Pair.Write.C().
+type Registry[K comparable] struct {
+	mutex sync.Mutex
+	pairs map[K]*Pair
+}
+
+// NewRegistry allocates an empty Registry.
+func NewRegistry[K comparable]() *Registry[K] {
+	return &Registry[K]{
+		pairs: make(map[K]*Pair),
+	}
+}
+
+// Add registers pair under id, for use by the session for the rest of its
+// lifetime.
+func (r *Registry[K]) Add(id K, pair *Pair) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pairs[id] = pair
+}
+
+// Remove deregisters id, typically called when the session closes.
+func (r *Registry[K]) Remove(id K) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.pairs, id)
+}
+
+// Get returns the Pair registered under id, or nil if there's none.
+func (r *Registry[K]) Get(id K) *Pair {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.pairs[id]
+}
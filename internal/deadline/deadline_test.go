@@ -0,0 +1,65 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerFiresAfterSet(t *testing.T) {
+	timer := NewTimer()
+	timer.Set(10 * time.Millisecond)
+
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+func TestTimerRearmCancelsPrevious(t *testing.T) {
+	timer := NewTimer()
+	timer.Set(10 * time.Millisecond)
+	stale := timer.C()
+
+	// rearming before expiry must cancel the pending timer: the stale
+	// channel from before the call must never close on its own.
+	timer.Set(time.Hour)
+
+	select {
+	case <-stale:
+		t.Fatal("stale channel fired even though it was superseded by Set")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	select {
+	case <-timer.C():
+		t.Fatal("new deadline fired too early")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTimerZeroDurationFiresImmediately(t *testing.T) {
+	timer := NewTimer()
+	timer.Set(0)
+
+	select {
+	case <-timer.C():
+	default:
+		t.Fatal("zero duration must fire immediately")
+	}
+}
+
+func TestRegistryAddGetRemove(t *testing.T) {
+	reg := NewRegistry[string]()
+	pair := NewPair()
+
+	require.Nil(t, reg.Get("a"))
+
+	reg.Add("a", pair)
+	require.Equal(t, pair, reg.Get("a"))
+
+	reg.Remove("a")
+	require.Nil(t, reg.Get("a"))
+}